@@ -0,0 +1,140 @@
+// Package admin implements a small runtime-configurable mock surface: an operator can register
+// custom routes and redirects over HTTP, without restarting the process, and have them take
+// priority over httpbun's built-in fixtures.
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+)
+
+// Route is an operator-defined mock: requests matching Pattern (and, if set, Methods) get the
+// literal or templated Response instead of reaching the built-in fixtures.
+type Route struct {
+	ID      string            `json:"id"`
+	Pattern string            `json:"pattern"`
+	Methods []string          `json:"methods,omitempty"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+	DelayMs int               `json:"delay_ms,omitempty"`
+
+	compiled *regexp.Regexp
+}
+
+// Redirect is an operator-defined `Location` response: requests matching Pattern get a redirect
+// to Target (itself template-able, same as Route.Body).
+type Redirect struct {
+	ID      string `json:"id"`
+	Pattern string `json:"pattern"`
+	Target  string `json:"target"`
+	Status  int    `json:"status,omitempty"`
+
+	compiled *regexp.Regexp
+}
+
+// state is the on-disk (and in-memory) shape persisted to HTTPBUN_ROUTES_FILE.
+type state struct {
+	Routes    []*Route    `json:"routes"`
+	Redirects []*Redirect `json:"redirects"`
+}
+
+// Registry holds the operator-defined routes and redirects currently in effect. It's safe for
+// concurrent use from request-handling goroutines and the admin API itself.
+type Registry struct {
+	mu        sync.RWMutex
+	routes    []*Route
+	redirects []*Redirect
+	filePath  string
+	nextID    int
+}
+
+// NewRegistry creates an empty registry, optionally loading previously-persisted state from
+// filePath (the HTTPBUN_ROUTES_FILE env var). A missing file is not an error; it just means
+// there's nothing to load yet.
+func NewRegistry(filePath string) *Registry {
+	r := &Registry{filePath: filePath}
+
+	if filePath != "" {
+		if err := r.load(); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "admin: error loading %s: %v\n", filePath, err)
+		}
+	}
+
+	return r
+}
+
+func (r *Registry) load() error {
+	bytes, err := os.ReadFile(r.filePath)
+	if err != nil {
+		return err
+	}
+
+	var s state
+	if err := json.Unmarshal(bytes, &s); err != nil {
+		return err
+	}
+
+	routes := s.Routes[:0]
+	for _, route := range s.Routes {
+		compiled, err := regexp.Compile(route.Pattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "admin: skipping persisted route %q with invalid pattern %q: %v\n", route.ID, route.Pattern, err)
+			continue
+		}
+		route.compiled = compiled
+		if id, err := parseID(route.ID); err == nil && id >= r.nextID {
+			r.nextID = id + 1
+		}
+		routes = append(routes, route)
+	}
+
+	redirects := s.Redirects[:0]
+	for _, redirect := range s.Redirects {
+		compiled, err := regexp.Compile(redirect.Pattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "admin: skipping persisted redirect %q with invalid pattern %q: %v\n", redirect.ID, redirect.Pattern, err)
+			continue
+		}
+		redirect.compiled = compiled
+		if id, err := parseID(redirect.ID); err == nil && id >= r.nextID {
+			r.nextID = id + 1
+		}
+		redirects = append(redirects, redirect)
+	}
+
+	r.routes = routes
+	r.redirects = redirects
+
+	return nil
+}
+
+func (r *Registry) persist() {
+	if r.filePath == "" {
+		return
+	}
+
+	bytes, err := json.MarshalIndent(state{Routes: r.routes, Redirects: r.redirects}, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "admin: error encoding state: %v\n", err)
+		return
+	}
+
+	if err := os.WriteFile(r.filePath, bytes, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "admin: error writing %s: %v\n", r.filePath, err)
+	}
+}
+
+func parseID(id string) (int, error) {
+	var n int
+	_, err := fmt.Sscanf(id, "%d", &n)
+	return n, err
+}
+
+func (r *Registry) newID() string {
+	r.nextID++
+	return fmt.Sprintf("%d", r.nextID)
+}
@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewRegistrySkipsInvalidPersistedPattern(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	fixture := `{
+		"routes": [
+			{"id": "1", "pattern": "(unterminated", "status": 200, "body": "bad"},
+			{"id": "2", "pattern": "^/ok$", "status": 200, "body": "good"}
+		],
+		"redirects": []
+	}`
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	r := NewRegistry(path)
+
+	if len(r.routes) != 1 || r.routes[0].ID != "2" {
+		t.Fatalf("routes = %+v, want only the route with a valid pattern", r.routes)
+	}
+}
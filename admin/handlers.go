@@ -0,0 +1,154 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/sharat87/httpbun/exchange"
+	"github.com/sharat87/httpbun/util"
+)
+
+// Authenticate reports whether ex carries the configured admin bearer token. Every admin handler
+// checks this first.
+func Authenticate(ex *exchange.Exchange, token string) bool {
+	return token != "" && util.ConstantTimeEqual(ex.Request.Header.Get("Authorization"), "Bearer "+token)
+}
+
+// HandleRoutes implements GET/POST/DELETE /_admin/routes(/:id).
+func (r *Registry) HandleRoutes(ex *exchange.Exchange) {
+	switch ex.Request.Method {
+	case http.MethodGet:
+		r.mu.RLock()
+		routes := append([]*Route{}, r.routes...)
+		r.mu.RUnlock()
+		ex.WriteJSON(routes)
+
+	case http.MethodPost:
+		var route Route
+		if err := json.NewDecoder(ex.CappedBody).Decode(&route); err != nil {
+			ex.RespondError(http.StatusBadRequest, "invalid_json", err.Error())
+			return
+		}
+
+		compiled, err := regexp.Compile(route.Pattern)
+		if err != nil {
+			ex.RespondError(http.StatusBadRequest, "invalid_pattern", err.Error())
+			return
+		}
+		route.compiled = compiled
+
+		r.mu.Lock()
+		route.ID = r.newID()
+		r.routes = append(r.routes, &route)
+		r.persist()
+		r.mu.Unlock()
+
+		ex.ResponseWriter.WriteHeader(http.StatusCreated)
+		ex.WriteJSON(route)
+
+	case http.MethodDelete:
+		r.deleteRoute(ex, ex.Field("id"))
+
+	default:
+		ex.RespondWithStatus(http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *Registry) deleteRoute(ex *exchange.Exchange, id string) {
+	if id == "" {
+		ex.RespondError(http.StatusBadRequest, "missing_id", "route id is required")
+		return
+	}
+
+	r.mu.Lock()
+	kept := r.routes[:0]
+	removed := false
+	for _, route := range r.routes {
+		if route.ID == id {
+			removed = true
+			continue
+		}
+		kept = append(kept, route)
+	}
+	r.routes = kept
+	if removed {
+		r.persist()
+	}
+	r.mu.Unlock()
+
+	if !removed {
+		ex.RespondWithStatus(http.StatusNotFound)
+		return
+	}
+	ex.RespondWithStatus(http.StatusNoContent)
+}
+
+// HandleRedirects implements GET/POST/DELETE /_admin/redirects(/:id).
+func (r *Registry) HandleRedirects(ex *exchange.Exchange) {
+	switch ex.Request.Method {
+	case http.MethodGet:
+		r.mu.RLock()
+		redirects := append([]*Redirect{}, r.redirects...)
+		r.mu.RUnlock()
+		ex.WriteJSON(redirects)
+
+	case http.MethodPost:
+		var redirect Redirect
+		if err := json.NewDecoder(ex.CappedBody).Decode(&redirect); err != nil {
+			ex.RespondError(http.StatusBadRequest, "invalid_json", err.Error())
+			return
+		}
+
+		compiled, err := regexp.Compile(redirect.Pattern)
+		if err != nil {
+			ex.RespondError(http.StatusBadRequest, "invalid_pattern", err.Error())
+			return
+		}
+		redirect.compiled = compiled
+
+		r.mu.Lock()
+		redirect.ID = r.newID()
+		r.redirects = append(r.redirects, &redirect)
+		r.persist()
+		r.mu.Unlock()
+
+		ex.ResponseWriter.WriteHeader(http.StatusCreated)
+		ex.WriteJSON(redirect)
+
+	case http.MethodDelete:
+		r.deleteRedirect(ex, ex.Field("id"))
+
+	default:
+		ex.RespondWithStatus(http.StatusMethodNotAllowed)
+	}
+}
+
+func (r *Registry) deleteRedirect(ex *exchange.Exchange, id string) {
+	if id == "" {
+		ex.RespondError(http.StatusBadRequest, "missing_id", "redirect id is required")
+		return
+	}
+
+	r.mu.Lock()
+	kept := r.redirects[:0]
+	removed := false
+	for _, redirect := range r.redirects {
+		if redirect.ID == id {
+			removed = true
+			continue
+		}
+		kept = append(kept, redirect)
+	}
+	r.redirects = kept
+	if removed {
+		r.persist()
+	}
+	r.mu.Unlock()
+
+	if !removed {
+		ex.RespondWithStatus(http.StatusNotFound)
+		return
+	}
+	ex.RespondWithStatus(http.StatusNoContent)
+}
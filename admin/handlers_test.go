@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sharat87/httpbun/exchange"
+	"github.com/sharat87/httpbun/server/spec"
+)
+
+func newExchange(method, target, body string) *exchange.Exchange {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return exchange.New(httptest.NewRecorder(), req, spec.Spec{})
+}
+
+func TestHandleRoutesCreateListAndDelete(t *testing.T) {
+	r := NewRegistry("")
+
+	createEx := newExchange("POST", "/_admin/routes", `{"pattern": "^/mock$", "status": 200, "body": "hi"}`)
+	r.HandleRoutes(createEx)
+
+	if status := createEx.ResponseStatus(); status != 201 {
+		t.Fatalf("POST status = %d, want 201", status)
+	}
+
+	r.mu.RLock()
+	if len(r.routes) != 1 {
+		t.Fatalf("len(r.routes) = %d, want 1", len(r.routes))
+	}
+	id := r.routes[0].ID
+	if id == "" {
+		t.Error("expected the created route to be assigned a non-empty ID")
+	}
+	r.mu.RUnlock()
+
+	listEx := newExchange("GET", "/_admin/routes", "")
+	r.HandleRoutes(listEx)
+	if status := listEx.ResponseStatus(); status != 200 {
+		t.Errorf("GET status = %d, want 200", status)
+	}
+
+	deleteEx := newExchange("DELETE", "/_admin/routes/"+id, "")
+	deleteEx.Fields["id"] = id
+	r.HandleRoutes(deleteEx)
+	if status := deleteEx.ResponseStatus(); status != 204 {
+		t.Errorf("DELETE status = %d, want 204", status)
+	}
+
+	r.mu.RLock()
+	if len(r.routes) != 0 {
+		t.Errorf("len(r.routes) = %d, want 0 after delete", len(r.routes))
+	}
+	r.mu.RUnlock()
+
+	missingEx := newExchange("DELETE", "/_admin/routes/"+id, "")
+	missingEx.Fields["id"] = id
+	r.HandleRoutes(missingEx)
+	if status := missingEx.ResponseStatus(); status != 404 {
+		t.Errorf("DELETE of an already-deleted id status = %d, want 404", status)
+	}
+}
+
+func TestHandleRoutesRejectsInvalidPatternAndJSON(t *testing.T) {
+	r := NewRegistry("")
+
+	badPatternEx := newExchange("POST", "/_admin/routes", `{"pattern": "(unterminated"}`)
+	r.HandleRoutes(badPatternEx)
+	if status := badPatternEx.ResponseStatus(); status != 400 {
+		t.Errorf("invalid pattern status = %d, want 400", status)
+	}
+
+	badJSONEx := newExchange("POST", "/_admin/routes", `not json`)
+	r.HandleRoutes(badJSONEx)
+	if status := badJSONEx.ResponseStatus(); status != 400 {
+		t.Errorf("invalid JSON status = %d, want 400", status)
+	}
+
+	r.mu.RLock()
+	if len(r.routes) != 0 {
+		t.Errorf("len(r.routes) = %d, want 0 after rejected POSTs", len(r.routes))
+	}
+	r.mu.RUnlock()
+}
+
+func TestHandleRoutesRejectsUnsupportedMethod(t *testing.T) {
+	r := NewRegistry("")
+
+	ex := newExchange("PUT", "/_admin/routes", "")
+	r.HandleRoutes(ex)
+	if status := ex.ResponseStatus(); status != 405 {
+		t.Errorf("PUT status = %d, want 405", status)
+	}
+}
+
+func TestHandleRedirectsCreateAndDelete(t *testing.T) {
+	r := NewRegistry("")
+
+	createEx := newExchange("POST", "/_admin/redirects", `{"pattern": "^/old$", "target": "/new"}`)
+	r.HandleRedirects(createEx)
+	if status := createEx.ResponseStatus(); status != 201 {
+		t.Fatalf("POST status = %d, want 201", status)
+	}
+
+	r.mu.RLock()
+	if len(r.redirects) != 1 {
+		t.Fatalf("len(r.redirects) = %d, want 1", len(r.redirects))
+	}
+	id := r.redirects[0].ID
+	r.mu.RUnlock()
+
+	deleteEx := newExchange("DELETE", "/_admin/redirects/"+id, "")
+	deleteEx.Fields["id"] = id
+	r.HandleRedirects(deleteEx)
+	if status := deleteEx.ResponseStatus(); status != 204 {
+		t.Errorf("DELETE status = %d, want 204", status)
+	}
+}
+
+func TestRegistryPersistsAndReloadsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+
+	r := NewRegistry(path)
+	createEx := newExchange("POST", "/_admin/routes", `{"pattern": "^/mock$", "status": 200, "body": "hi"}`)
+	r.HandleRoutes(createEx)
+
+	r.mu.RLock()
+	firstID := r.routes[0].ID
+	r.mu.RUnlock()
+
+	// Simulate a restart: a fresh Registry loading the same persisted file should pick up the
+	// route, and continue ID allocation after the highest persisted ID instead of reusing it.
+	reloaded := NewRegistry(path)
+
+	reloaded.mu.RLock()
+	if len(reloaded.routes) != 1 || reloaded.routes[0].ID != firstID {
+		t.Fatalf("reloaded routes = %+v, want one route with ID %q", reloaded.routes, firstID)
+	}
+	reloaded.mu.RUnlock()
+
+	secondEx := newExchange("POST", "/_admin/routes", `{"pattern": "^/mock2$", "status": 200, "body": "hi2"}`)
+	reloaded.HandleRoutes(secondEx)
+
+	reloaded.mu.RLock()
+	defer reloaded.mu.RUnlock()
+	if len(reloaded.routes) != 2 {
+		t.Fatalf("len(reloaded.routes) = %d, want 2", len(reloaded.routes))
+	}
+	if reloaded.routes[1].ID == firstID {
+		t.Errorf("expected the second route's ID (%q) to differ from the reloaded one (%q)", reloaded.routes[1].ID, firstID)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	ex := newExchange("GET", "/_admin/routes", "")
+	ex.Request.Header.Set("Authorization", "Bearer s3cret")
+
+	if !Authenticate(ex, "s3cret") {
+		t.Error("expected Authenticate to accept the matching bearer token")
+	}
+	if Authenticate(ex, "wrong") {
+		t.Error("expected Authenticate to reject a mismatched token")
+	}
+	if Authenticate(ex, "") {
+		t.Error("expected Authenticate to reject when no token is configured")
+	}
+}
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sharat87/httpbun/exchange"
+)
+
+// MatchRoute returns the first registered route whose pattern matches path and whose method
+// filter (if any) allows method, or nil if none match. path should be PathPrefix-trimmed, the
+// same as every built-in route matches against (see exchange.New), so a registered pattern like
+// "^/foo$" means the same thing here as it does for a built-in route.
+func (r *Registry) MatchRoute(path, method string) *Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, route := range r.routes {
+		if route.compiled.MatchString(path) && methodAllowed(route.Methods, method) {
+			return route
+		}
+	}
+
+	return nil
+}
+
+// MatchRedirect returns the first registered redirect whose pattern matches path, or nil if none
+// match. path should be PathPrefix-trimmed, same as MatchRoute.
+func (r *Registry) MatchRedirect(path string) *Redirect {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, redirect := range r.redirects {
+		if redirect.compiled.MatchString(path) {
+			return redirect
+		}
+	}
+
+	return nil
+}
+
+func methodAllowed(methods []string, method string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Serve writes route's configured response for ex, after applying its delay (if any) and
+// rendering Body as a text/template against the request.
+func (route *Route) Serve(ex *exchange.Exchange) {
+	if route.DelayMs > 0 {
+		time.Sleep(time.Duration(route.DelayMs) * time.Millisecond)
+	}
+
+	for name, value := range route.Headers {
+		ex.ResponseWriter.Header().Set(name, value)
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ex.ResponseWriter.WriteHeader(status)
+
+	body, err := render(route.Body, ex.Request)
+	if err != nil {
+		ex.Write(route.Body)
+		return
+	}
+	ex.Write(body)
+}
+
+// Serve redirects ex's request to redirect's (possibly templated) Target.
+func (redirect *Redirect) Serve(ex *exchange.Exchange) {
+	target, err := render(redirect.Target, ex.Request)
+	if err != nil {
+		target = redirect.Target
+	}
+
+	status := redirect.Status
+	if status == 0 {
+		status = http.StatusFound
+	}
+
+	ex.ResponseWriter.Header().Set("Location", target)
+	ex.ResponseWriter.WriteHeader(status)
+}
+
+// render evaluates a body/target template against req, exposing {{.method}}, {{.path}},
+// {{.query.<name>}}, and {{.header.<Name>}}.
+func render(tpl string, req *http.Request) (string, error) {
+	t, err := template.New("admin").Parse(tpl)
+	if err != nil {
+		return "", err
+	}
+
+	data := map[string]any{
+		"method": req.Method,
+		"path":   req.URL.Path,
+		"query":  firstValues(req.URL.Query()),
+		"header": firstValues(req.Header),
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func firstValues(values map[string][]string) map[string]string {
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		if len(v) > 0 {
+			out[k] = v[0]
+		}
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	req := &http.Request{
+		Method: "GET",
+		URL:    &url.URL{Path: "/hello", RawQuery: "foo=bar"},
+		Header: http.Header{"Foo": []string{"baz"}},
+	}
+
+	out, err := render("{{.method}} {{.path}} {{.query.foo}} {{.header.Foo}}", req)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+
+	const want = "GET /hello bar baz"
+	if out != want {
+		t.Errorf("render() = %q, want %q", out, want)
+	}
+}
+
+func TestMatchRouteHonorsMethodFilter(t *testing.T) {
+	r := NewRegistry("")
+	r.routes = []*Route{
+		{ID: "1", Pattern: `^/only-post$`, Methods: []string{"POST"}, compiled: regexp.MustCompile(`^/only-post$`)},
+	}
+
+	if r.MatchRoute("/only-post", "GET") != nil {
+		t.Error("expected no match for GET against a POST-only route")
+	}
+
+	if r.MatchRoute("/only-post", "POST") == nil {
+		t.Error("expected a match for POST against a POST-only route")
+	}
+}
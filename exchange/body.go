@@ -0,0 +1,157 @@
+package exchange
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// formMaxMemory is the maxMemory argument for Request.ParseMultipartForm. It's unrelated to the
+// request-size cap (ex.CappedBody already bounds the body read to 10000 bytes); it only controls
+// how much of that capped body multipart parsing is willing to hold in memory versus spill to
+// temp files, so we just use net/http's own documented default.
+const formMaxMemory = 32 << 20
+
+// BindBody decodes the request body into target (a pointer to a struct), choosing a decoder from
+// the request's Content-Type: application/json and application/xml/text/xml decode directly;
+// application/x-www-form-urlencoded and multipart/form-data decode field-by-field using each
+// struct field's `form` tag. Any other (or missing) Content-Type falls back to JSON, httpbun's
+// long-standing default. An empty body is rejected with a 400 via RespondError; on error, the
+// response has already been written and callers should just return.
+func (ex Exchange) BindBody(target any) error {
+	mediaType, _, err := mime.ParseMediaType(ex.Request.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ex.Request.Header.Get("Content-Type")
+	}
+
+	switch mediaType {
+	case "application/xml", "text/xml":
+		return ex.bindXML(target)
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		return ex.bindForm(mediaType, target)
+	default:
+		return ex.bindJSON(target)
+	}
+}
+
+func (ex Exchange) bindJSON(target any) error {
+	body := ex.BodyBytes()
+	if len(body) == 0 {
+		return ex.emptyBodyError()
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		ex.RespondError(http.StatusBadRequest, "invalid_json", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (ex Exchange) bindXML(target any) error {
+	body := ex.BodyBytes()
+	if len(body) == 0 {
+		return ex.emptyBodyError()
+	}
+
+	if err := xml.Unmarshal(body, target); err != nil {
+		ex.RespondError(http.StatusBadRequest, "invalid_xml", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (ex Exchange) bindForm(mediaType string, target any) error {
+	// Route the parse through ex.CappedBody, same as every other BindBody path, instead of
+	// ParseForm/ParseMultipartForm reading ex.Request.Body directly and bypassing the cap.
+	ex.Request.Body = io.NopCloser(ex.CappedBody)
+
+	var err error
+	if mediaType == "multipart/form-data" {
+		err = ex.Request.ParseMultipartForm(formMaxMemory)
+	} else {
+		err = ex.Request.ParseForm()
+	}
+
+	if err != nil {
+		ex.RespondError(http.StatusBadRequest, "invalid_form", err.Error())
+		return err
+	}
+
+	if len(ex.Request.Form) == 0 {
+		return ex.emptyBodyError()
+	}
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		err := fmt.Errorf("BindBody target must be a pointer to a struct, got %T", target)
+		ex.RespondError(http.StatusInternalServerError, "bad_bind_target", err.Error())
+		return err
+	}
+
+	elem := value.Elem()
+	typ := elem.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("form")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		values := ex.Request.Form[tag]
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := setFormField(elem.Field(i), values[0]); err != nil {
+			ex.RespondError(http.StatusBadRequest, "invalid_form", err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setFormField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+
+	default:
+		return fmt.Errorf("unsupported form field kind %s", field.Kind())
+	}
+
+	return nil
+}
+
+func (ex Exchange) emptyBodyError() error {
+	ex.RespondError(http.StatusBadRequest, "empty_body", "request body is empty")
+	return fmt.Errorf("request body is empty")
+}
@@ -0,0 +1,153 @@
+package exchange
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sharat87/httpbun/server/spec"
+)
+
+type bindTarget struct {
+	Name   string  `json:"name" xml:"name" form:"name"`
+	Age    int     `json:"age" xml:"age" form:"age"`
+	Active bool    `json:"active" xml:"active" form:"active"`
+	Score  float64 `json:"score" xml:"score" form:"score"`
+}
+
+func newBindExchange(method, contentType, body string) *Exchange {
+	req := httptest.NewRequest(method, "/anything", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return New(httptest.NewRecorder(), req, spec.Spec{})
+}
+
+func TestBindBodyDecodesJSON(t *testing.T) {
+	ex := newBindExchange("POST", "application/json", `{"name": "ann", "age": 30, "active": true, "score": 1.5}`)
+
+	var target bindTarget
+	if err := ex.BindBody(&target); err != nil {
+		t.Fatalf("BindBody returned error: %v", err)
+	}
+	if target != (bindTarget{Name: "ann", Age: 30, Active: true, Score: 1.5}) {
+		t.Errorf("target = %+v, want {ann 30 true 1.5}", target)
+	}
+}
+
+func TestBindBodyDefaultsToJSONForUnknownContentType(t *testing.T) {
+	ex := newBindExchange("POST", "application/octet-stream", `{"name": "ann"}`)
+
+	var target bindTarget
+	if err := ex.BindBody(&target); err != nil {
+		t.Fatalf("BindBody returned error: %v", err)
+	}
+	if target.Name != "ann" {
+		t.Errorf("target.Name = %q, want %q", target.Name, "ann")
+	}
+}
+
+func TestBindBodyRejectsEmptyJSONBody(t *testing.T) {
+	ex := newBindExchange("POST", "application/json", "")
+
+	var target bindTarget
+	err := ex.BindBody(&target)
+	if err == nil {
+		t.Fatal("expected an error for an empty body")
+	}
+	if status := ex.ResponseStatus(); status != 400 {
+		t.Errorf("status = %d, want 400", status)
+	}
+}
+
+func TestBindBodyReportsInvalidJSON(t *testing.T) {
+	ex := newBindExchange("POST", "application/json", "not json")
+
+	var target bindTarget
+	if err := ex.BindBody(&target); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+	if status := ex.ResponseStatus(); status != 400 {
+		t.Errorf("status = %d, want 400", status)
+	}
+}
+
+func TestBindBodyDecodesXML(t *testing.T) {
+	ex := newBindExchange("POST", "application/xml", `<bindTarget><name>ann</name><age>30</age></bindTarget>`)
+
+	var target bindTarget
+	if err := ex.BindBody(&target); err != nil {
+		t.Fatalf("BindBody returned error: %v", err)
+	}
+	if target.Name != "ann" || target.Age != 30 {
+		t.Errorf("target = %+v, want Name=ann Age=30", target)
+	}
+}
+
+func TestBindBodyReportsInvalidXML(t *testing.T) {
+	ex := newBindExchange("POST", "text/xml", "<unterminated")
+
+	var target bindTarget
+	if err := ex.BindBody(&target); err == nil {
+		t.Fatal("expected an error for invalid XML")
+	}
+	if status := ex.ResponseStatus(); status != 400 {
+		t.Errorf("status = %d, want 400", status)
+	}
+}
+
+func TestBindBodyDecodesFormFields(t *testing.T) {
+	ex := newBindExchange("POST", "application/x-www-form-urlencoded", "name=ann&age=30&active=true&score=1.5")
+
+	var target bindTarget
+	if err := ex.BindBody(&target); err != nil {
+		t.Fatalf("BindBody returned error: %v", err)
+	}
+	if target != (bindTarget{Name: "ann", Age: 30, Active: true, Score: 1.5}) {
+		t.Errorf("target = %+v, want {ann 30 true 1.5}", target)
+	}
+}
+
+func TestBindBodyRejectsEmptyForm(t *testing.T) {
+	ex := newBindExchange("POST", "application/x-www-form-urlencoded", "")
+
+	var target bindTarget
+	if err := ex.BindBody(&target); err == nil {
+		t.Fatal("expected an error for an empty form body")
+	}
+	if status := ex.ResponseStatus(); status != 400 {
+		t.Errorf("status = %d, want 400", status)
+	}
+}
+
+func TestBindBodyDecodesMultipartForm(t *testing.T) {
+	body := "--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"name\"\r\n\r\n" +
+		"ann\r\n" +
+		"--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"age\"\r\n\r\n" +
+		"30\r\n" +
+		"--boundary--\r\n"
+
+	ex := newBindExchange("POST", "multipart/form-data; boundary=boundary", body)
+
+	var target bindTarget
+	if err := ex.BindBody(&target); err != nil {
+		t.Fatalf("BindBody returned error: %v", err)
+	}
+	if target.Name != "ann" || target.Age != 30 {
+		t.Errorf("target = %+v, want Name=ann Age=30", target)
+	}
+}
+
+func TestSetFormFieldRejectsInvalidNumbers(t *testing.T) {
+	ex := newBindExchange("POST", "application/x-www-form-urlencoded", "age=not-a-number")
+
+	var target bindTarget
+	if err := ex.BindBody(&target); err == nil {
+		t.Fatal("expected an error for a non-numeric age field")
+	}
+	if status := ex.ResponseStatus(); status != 400 {
+		t.Errorf("status = %d, want 400", status)
+	}
+}
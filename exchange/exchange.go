@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"fmt"
+	"github.com/sharat87/httpbun/server/middleware"
 	"github.com/sharat87/httpbun/server/spec"
 	"github.com/sharat87/httpbun/util"
 	"io"
@@ -22,15 +23,19 @@ type Exchange struct {
 	CappedBody     io.Reader
 	URL            *url.URL
 	ServerSpec     spec.Spec
+	recorder       *statusRecorder
 }
 
 type HandlerFn func(ex *Exchange)
 
 func New(w http.ResponseWriter, req *http.Request, serverSpec spec.Spec) *Exchange {
+	recorder := newStatusRecorder(w)
+
 	ex := &Exchange{
 		Request:        req,
-		ResponseWriter: w,
+		ResponseWriter: recorder,
 		Fields:         map[string]string{},
+		recorder:       recorder,
 		CappedBody:     io.LimitReader(req.Body, 10000),
 		URL: &url.URL{
 			Scheme:      req.URL.Scheme,
@@ -51,41 +56,98 @@ func New(w http.ResponseWriter, req *http.Request, serverSpec spec.Spec) *Exchan
 		ex.URL.Host = req.Host
 	}
 
-	// Need to set the exact origin, since `*` won't work if request includes credentials.
-	// See <https://developer.mozilla.org/en-US/docs/Web/HTTP/CORS/Errors/CORSNotSupportingCredentials>.
-	originHeader := ex.HeaderValueLast("Origin")
-	if originHeader != "" {
-		ex.ResponseWriter.Header().Set("Access-Control-Allow-Origin", originHeader)
-		ex.ResponseWriter.Header().Set("Access-Control-Allow-Credentials", "true")
+	// CORS headers and trusted-proxy forwarding are handled by the middleware chain in
+	// server.StartNew, ahead of exchange construction, so Request.RemoteAddr/Host are already
+	// normalized by the time we get here.
+
+	ex.ResponseWriter.Header().Set("X-Powered-By", "httpbun/"+serverSpec.Commit)
+
+	return ex
+}
+
+// statusRecorder wraps a ResponseWriter to capture the status code and byte count written, for
+// instrumentation (see Exchange.ResponseStatus, Exchange.ResponseSize) without changing how
+// handlers write responses.
+type statusRecorder struct {
+	http.ResponseWriter
+	status       int
+	bytesWritten int
+}
+
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(p)
+	r.bytesWritten += n
+	return n, err
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
 	}
+}
+
+// ResponseStatus returns the status code written for this exchange, or 200 if WriteHeader was
+// never called explicitly (matching net/http's implicit-200 behavior).
+func (ex Exchange) ResponseStatus() int {
+	return ex.recorder.status
+}
 
-	accessControlHeaders := ex.Request.Header.Get("Access-Control-Request-Headers")
-	if accessControlHeaders != "" {
-		ex.ResponseWriter.Header().Set("Access-Control-Allow-Headers", accessControlHeaders)
+// ResponseSize returns the number of response body bytes written so far.
+func (ex Exchange) ResponseSize() int {
+	return ex.recorder.bytesWritten
+}
+
+// MatchAndLoadFields reports whether this exchange's request matches pathPat (and, if hostPat is
+// non-nil, the request's Host also matches hostPat), loading any named capture groups from both
+// patterns into ex.Fields as a side effect of a successful match. Fields are only committed once
+// both patterns have matched, so a host match followed by a path miss leaves ex.Fields untouched.
+func (ex Exchange) MatchAndLoadFields(hostPat *regexp.Regexp, pathPat regexp.Regexp) bool {
+	fields := map[string]string{}
+
+	if hostPat != nil && !loadFields(fields, hostPat, requestHost(ex.Request)) {
+		return false
 	}
 
-	accessControlMethods := ex.Request.Header.Get("Access-Control-Request-Method")
-	if accessControlMethods != "" {
-		ex.ResponseWriter.Header().Set("Access-Control-Allow-Methods", accessControlMethods)
+	if !loadFields(fields, &pathPat, ex.URL.Path) {
+		return false
 	}
 
-	ex.ResponseWriter.Header().Set("X-Powered-By", "httpbun/"+serverSpec.Commit)
+	for name, value := range fields {
+		ex.Fields[name] = value
+	}
 
-	return ex
+	return true
 }
 
-func (ex Exchange) MatchAndLoadFields(routePat regexp.Regexp) bool {
-	match := routePat.FindStringSubmatch(ex.URL.Path)
-	if match != nil {
-		names := routePat.SubexpNames()
-		for i, name := range names {
-			if name != "" {
-				ex.Fields[name] = match[i]
-			}
+func requestHost(req *http.Request) string {
+	if host, _, err := net.SplitHostPort(req.Host); err == nil {
+		return host
+	}
+	return req.Host
+}
+
+func loadFields(fields map[string]string, pat *regexp.Regexp, value string) bool {
+	match := pat.FindStringSubmatch(value)
+	if match == nil {
+		return false
+	}
+
+	for i, name := range pat.SubexpNames() {
+		if name != "" {
+			fields[name] = match[i]
 		}
-		return true
 	}
-	return false
+
+	return true
 }
 
 func (ex Exchange) Field(name string) string {
@@ -167,11 +229,11 @@ func (ex Exchange) ExposableHeadersMap() map[string]any {
 }
 
 func (ex Exchange) FindScheme() string {
-	if forwardedProto := ex.HeaderValueLast("X-Httpbun-Forwarded-Proto"); forwardedProto != "" {
-		return forwardedProto
+	if scheme, ok := middleware.SchemeFromContext(ex.Request.Context()); ok {
+		return scheme
 	}
 
-	if os.Getenv("HTTPBUN_SSL_CERT") != "" || ex.HeaderValueLast("X-Httpbun-Forwarded-Proto") == "https" {
+	if ex.Request.TLS != nil || os.Getenv("HTTPBUN_SSL_CERT") != "" {
 		return "https"
 	}
 
@@ -186,21 +248,22 @@ func (ex Exchange) FullUrl() string {
 	return ex.FindScheme() + "://" + ex.Request.Host + ex.Request.URL.String()
 }
 
-// FindIncomingIPAddress Find the IP address of the client that made this Exchange.
+// FindIncomingIPAddress Find the IP address of the client that made this Exchange. When the
+// immediate peer is a trusted proxy, the middleware.ProxyHeaders middleware has already rewritten
+// Request.RemoteAddr from the forwarding headers, so this just reads it off the connection.
+// Compare with <http://httpbin.org/ip> or <http://checkip.amazonaws.com/> or <http://getmyip.co.in/>.
 func (ex Exchange) FindIncomingIPAddress() string {
-	// Compare with <http://httpbin.org/ip> or <http://checkip.amazonaws.com/> or <http://getmyip.co.in/>.
-	ipStr := ex.HeaderValueLast("X-Httpbun-Forwarded-For")
-
-	// If that's also not available, get it directly from the connection.
-	if ipStr == "" {
-		if ip, _, err := net.SplitHostPort(ex.Request.RemoteAddr); err != nil {
-			log.Printf("Unable to read IP from address %q.", ex.Request.RemoteAddr)
-		} else if userIP := net.ParseIP(ip); userIP != nil {
-			ipStr = userIP.String()
-		}
+	ip, _, err := net.SplitHostPort(ex.Request.RemoteAddr)
+	if err != nil {
+		log.Printf("Unable to read IP from address %q.", ex.Request.RemoteAddr)
+		return ex.Request.RemoteAddr
+	}
+
+	if userIP := net.ParseIP(ip); userIP != nil {
+		return userIP.String()
 	}
 
-	return ipStr
+	return ip
 }
 
 func (ex Exchange) BodyBytes() []byte {
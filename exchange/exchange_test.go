@@ -0,0 +1,43 @@
+package exchange
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/sharat87/httpbun/server/spec"
+)
+
+func TestMatchAndLoadFieldsDoesNotLeakFieldsOnPathMiss(t *testing.T) {
+	req := httptest.NewRequest("GET", "/other", nil)
+	req.Host = "tenant-a.mock.example.com"
+	ex := New(httptest.NewRecorder(), req, spec.Spec{})
+
+	hostPat := regexp.MustCompile(`^(?P<subdomain>[^.]+)\.mock\.example\.com$`)
+	pathPat := regexp.MustCompile(`^/host-info$`)
+
+	if ex.MatchAndLoadFields(hostPat, *pathPat) {
+		t.Fatal("MatchAndLoadFields() = true, want false for a host match with a path miss")
+	}
+
+	if _, ok := ex.Fields["subdomain"]; ok {
+		t.Errorf(`Fields["subdomain"] = %q, want unset after an overall match failure`, ex.Fields["subdomain"])
+	}
+}
+
+func TestMatchAndLoadFieldsLoadsFieldsOnFullMatch(t *testing.T) {
+	req := httptest.NewRequest("GET", "/host-info", nil)
+	req.Host = "tenant-a.mock.example.com"
+	ex := New(httptest.NewRecorder(), req, spec.Spec{})
+
+	hostPat := regexp.MustCompile(`^(?P<subdomain>[^.]+)\.mock\.example\.com$`)
+	pathPat := regexp.MustCompile(`^/host-info$`)
+
+	if !ex.MatchAndLoadFields(hostPat, *pathPat) {
+		t.Fatal("MatchAndLoadFields() = false, want true for a matching host and path")
+	}
+
+	if got := ex.Fields["subdomain"]; got != "tenant-a" {
+		t.Errorf(`Fields["subdomain"] = %q, want "tenant-a"`, got)
+	}
+}
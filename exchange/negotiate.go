@@ -0,0 +1,111 @@
+package exchange
+
+import (
+	"encoding/xml"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WriteNegotiated writes data as JSON, XML, or plain text, whichever the request's Accept header
+// prefers by q-value, falling back to JSON (httpbun's long-standing default) when the header is
+// absent or names nothing we support.
+func (ex Exchange) WriteNegotiated(data any) {
+	switch negotiateContentType(ex.Request.Header.Get("Accept")) {
+	case "application/xml":
+		ex.writeXML(data)
+	case "text/plain":
+		ex.ResponseWriter.Header().Set("Content-Type", "text/plain")
+		ex.WriteF("%+v", data)
+	default:
+		ex.WriteJSON(data)
+	}
+}
+
+func (ex Exchange) writeXML(data any) {
+	body, err := xml.Marshal(data)
+	if err != nil {
+		log.Printf("Error marshalling XML response %v", err)
+		return
+	}
+
+	ex.ResponseWriter.Header().Set("Content-Type", "application/xml")
+	ex.WriteBytes(body)
+}
+
+// mediaTypesByPreference maps an Accept-able media type to the content type WriteNegotiated
+// knows how to render, in the order ties should be broken.
+var mediaTypesByPreference = []struct {
+	accept string
+	mapped string
+}{
+	{"application/json", "application/json"},
+	{"application/xml", "application/xml"},
+	{"text/xml", "application/xml"},
+	{"text/plain", "text/plain"},
+	{"*/*", "application/json"},
+}
+
+// negotiateContentType picks the best-supported media type from an Accept header, by descending
+// q-value; ties are broken by mediaTypesByPreference's order. An empty or unsupported header
+// defaults to JSON.
+func negotiateContentType(accept string) string {
+	if accept == "" {
+		return "application/json"
+	}
+
+	type candidate struct {
+		mapped string
+		q      float64
+		rank   int
+	}
+
+	rankOf := func(accept string) (string, int, bool) {
+		for i, entry := range mediaTypesByPreference {
+			if entry.accept == accept {
+				return entry.mapped, i, true
+			}
+		}
+		return "", 0, false
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, q := parseAcceptPart(part)
+		if mapped, rank, ok := rankOf(mediaType); ok {
+			candidates = append(candidates, candidate{mapped, q, rank})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "application/json"
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].q != candidates[j].q {
+			return candidates[i].q > candidates[j].q
+		}
+		return candidates[i].rank < candidates[j].rank
+	})
+
+	return candidates[0].mapped
+}
+
+func parseAcceptPart(part string) (mediaType string, q float64) {
+	q = 1.0
+
+	fields := strings.Split(part, ";")
+	mediaType = strings.TrimSpace(fields[0])
+
+	for _, param := range fields[1:] {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+
+	return mediaType, q
+}
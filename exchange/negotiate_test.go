@@ -0,0 +1,25 @@
+package exchange
+
+import "testing"
+
+func TestNegotiateContentType(t *testing.T) {
+	cases := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"application/json", "application/json"},
+		{"application/xml", "application/xml"},
+		{"text/xml;q=0.9", "application/xml"},
+		{"text/plain", "text/plain"},
+		{"application/xml;q=0.5, application/json;q=0.9", "application/json"},
+		{"*/*", "application/json"},
+		{"image/png", "application/json"},
+	}
+
+	for _, c := range cases {
+		if got := negotiateContentType(c.accept); got != c.want {
+			t.Errorf("negotiateContentType(%q) = %q, want %q", c.accept, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sharat87/httpbun/util"
+)
+
+// SSEEvent is one Server-Sent Event, per the EventSource spec's event/data/id/retry fields.
+// Event, ID, and Retry are omitted from the wire format when left at their zero value.
+type SSEEvent struct {
+	Event string
+	Data  string
+	ID    string
+	Retry int
+}
+
+// Flush writes any buffered response data to the client immediately, if the underlying
+// ResponseWriter supports it. It's a no-op otherwise, so streaming helpers degrade gracefully
+// (without incremental delivery) behind a ResponseWriter that can't flush.
+func (ex Exchange) Flush() {
+	if f, ok := ex.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// StreamJSON writes each value received on ch as one line of newline-delimited JSON, flushing
+// after every record so clients see them as they arrive instead of buffered until ch closes. It
+// stops early if the client disconnects, or after HTTPBUN_MAX_STREAM_SECONDS, if set.
+func (ex Exchange) StreamJSON(ch <-chan any) {
+	ctx, cancel := streamContext(ex.Request.Context())
+	defer cancel()
+
+	ex.ResponseWriter.Header().Set("Content-Type", "application/x-ndjson")
+	ex.ResponseWriter.WriteHeader(http.StatusOK)
+	ex.Flush()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case value, ok := <-ch:
+			if !ok {
+				return
+			}
+			ex.WriteBytes(util.ToJsonMust(value))
+			ex.Write("\n")
+			ex.Flush()
+		}
+	}
+}
+
+// SSE streams ch as Server-Sent Events, emitting a heartbeat comment every heartbeatInterval to
+// keep idle connections (and the proxies between them) alive. A zero heartbeatInterval disables
+// heartbeats. It stops early if the client disconnects, or after HTTPBUN_MAX_STREAM_SECONDS, if
+// set.
+func (ex Exchange) SSE(ch <-chan SSEEvent, heartbeatInterval time.Duration) {
+	ctx, cancel := streamContext(ex.Request.Context())
+	defer cancel()
+
+	ex.ResponseWriter.Header().Set("Content-Type", "text/event-stream")
+	ex.ResponseWriter.Header().Set("Cache-Control", "no-cache")
+	ex.ResponseWriter.Header().Set("Connection", "keep-alive")
+	ex.ResponseWriter.WriteHeader(http.StatusOK)
+	ex.Flush()
+
+	var heartbeat <-chan time.Time
+	if heartbeatInterval > 0 {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-heartbeat:
+			ex.Write(": heartbeat\n\n")
+			ex.Flush()
+
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			ex.writeSSEEvent(event)
+			ex.Flush()
+		}
+	}
+}
+
+func (ex Exchange) writeSSEEvent(event SSEEvent) {
+	if event.Event != "" {
+		ex.WriteF("event: %s\n", event.Event)
+	}
+	if event.ID != "" {
+		ex.WriteF("id: %s\n", event.ID)
+	}
+	if event.Retry > 0 {
+		ex.WriteF("retry: %d\n", event.Retry)
+	}
+	for _, line := range strings.Split(event.Data, "\n") {
+		ex.WriteF("data: %s\n", line)
+	}
+	ex.Write("\n")
+}
+
+// StreamDeadline derives a context from the request's context that's additionally cancelled once
+// HTTPBUN_MAX_STREAM_SECONDS elapses, if that env var is set. Route handlers that generate a
+// stream's payload in their own goroutine (rather than having it ready on ch before calling
+// StreamJSON/SSE) should select on this alongside writing to ch, so generation stops as soon as
+// either the client disconnects or the server-side cap fires, instead of blocking forever on a
+// send that StreamJSON/SSE has already stopped reading.
+func (ex Exchange) StreamDeadline() (context.Context, context.CancelFunc) {
+	return streamContext(ex.Request.Context())
+}
+
+// streamContext derives a context from parent that's additionally cancelled once
+// HTTPBUN_MAX_STREAM_SECONDS elapses, if that env var is set to a positive integer. Callers must
+// always invoke the returned cancel, even when the env var is unset.
+func streamContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if raw := os.Getenv("HTTPBUN_MAX_STREAM_SECONDS"); raw != "" {
+		if seconds, err := strconv.Atoi(raw); err == nil && seconds > 0 {
+			return context.WithTimeout(parent, time.Duration(seconds)*time.Second)
+		}
+	}
+	return context.WithCancel(parent)
+}
@@ -0,0 +1,118 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sharat87/httpbun/server/spec"
+)
+
+func newTestExchange(ctx context.Context) (*Exchange, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest("GET", "/stream", nil)
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	rec := httptest.NewRecorder()
+	return New(rec, req, spec.Spec{}), rec
+}
+
+func TestStreamJSONWritesNDJSONLines(t *testing.T) {
+	ex, rec := newTestExchange(nil)
+
+	ch := make(chan any, 2)
+	ch <- map[string]any{"seq": 0}
+	ch <- map[string]any{"seq": 1}
+	close(ch)
+
+	ex.StreamJSON(ch)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want %q", ct, "application/x-ndjson")
+	}
+
+	lines := strings.Split(strings.TrimRight(rec.Body.String(), "\n"), "\n")
+	if len(lines) != 2 || lines[0] != `{"seq":0}` || lines[1] != `{"seq":1}` {
+		t.Errorf("lines = %v, want [{\"seq\":0} {\"seq\":1}]", lines)
+	}
+}
+
+func TestStreamJSONStopsOnClientDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ex, rec := newTestExchange(ctx)
+
+	// Never written to or closed; StreamJSON must return via ctx.Done() without reading it.
+	ch := make(chan any)
+	ex.StreamJSON(ch)
+
+	if rec.Body.Len() != 0 {
+		t.Errorf("expected no body written once the client context is already cancelled, got %q", rec.Body.String())
+	}
+}
+
+func TestSSEWritesEventDataIdRetry(t *testing.T) {
+	ex, rec := newTestExchange(nil)
+
+	ch := make(chan SSEEvent, 1)
+	ch <- SSEEvent{Event: "update", Data: "line1\nline2", ID: "42", Retry: 5000}
+	close(ch)
+
+	ex.SSE(ch, 0)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want %q", ct, "text/event-stream")
+	}
+
+	want := "event: update\nid: 42\nretry: 5000\ndata: line1\ndata: line2\n\n"
+	if rec.Body.String() != want {
+		t.Errorf("body = %q, want %q", rec.Body.String(), want)
+	}
+}
+
+func TestSSEEmitsHeartbeats(t *testing.T) {
+	ex, rec := newTestExchange(nil)
+
+	ch := make(chan SSEEvent)
+	go func() {
+		time.Sleep(60 * time.Millisecond)
+		close(ch)
+	}()
+
+	ex.SSE(ch, 15*time.Millisecond)
+
+	if !strings.Contains(rec.Body.String(), ": heartbeat\n\n") {
+		t.Errorf("expected at least one heartbeat comment in body, got %q", rec.Body.String())
+	}
+}
+
+func TestStreamDeadlineCancelsAfterMaxStreamSeconds(t *testing.T) {
+	t.Setenv("HTTPBUN_MAX_STREAM_SECONDS", "1")
+
+	ex, _ := newTestExchange(nil)
+	ctx, cancel := ex.StreamDeadline()
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(1500 * time.Millisecond):
+		t.Fatal("expected the stream context to be cancelled once HTTPBUN_MAX_STREAM_SECONDS elapses")
+	}
+}
+
+// noFlushWriter wraps a ResponseWriter without exposing http.Flusher, so Flush can be tested
+// against a writer that genuinely can't flush.
+type noFlushWriter struct {
+	http.ResponseWriter
+}
+
+func TestFlushDegradesGracefullyWithoutFlusher(t *testing.T) {
+	req := httptest.NewRequest("GET", "/stream", nil)
+	ex := New(noFlushWriter{httptest.NewRecorder()}, req, spec.Spec{})
+
+	ex.Flush() // must not panic
+}
@@ -0,0 +1,24 @@
+package metrics
+
+// histogram tracks observations against a fixed set of upper bounds (Prometheus "le" buckets),
+// plus the running sum and count needed to render a complete histogram metric family.
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.buckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
@@ -0,0 +1,155 @@
+// Package metrics implements a minimal Prometheus text-exposition-format exporter for httpbun's
+// own request-handling metrics (no third-party client library needed).
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sharat87/httpbun/exchange"
+	"github.com/sharat87/httpbun/util"
+)
+
+// DurationBuckets are the histogram bucket boundaries, in seconds, used for request duration.
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// SizeBuckets are the histogram bucket boundaries, in bytes, used for response size.
+var SizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+
+const labelSep = "\x1f"
+
+// Registry aggregates the request counter, duration/size histograms, and in-flight gauge
+// exposed on /metrics. The zero value is not usable; use NewRegistry.
+type Registry struct {
+	mu        sync.Mutex
+	requests  map[string]uint64
+	durations map[string]*histogram
+	sizes     map[string]*histogram
+	inFlight  int64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		requests:  map[string]uint64{},
+		durations: map[string]*histogram{},
+		sizes:     map[string]*histogram{},
+	}
+}
+
+// IncInFlight marks the start of a request being served.
+func (r *Registry) IncInFlight() {
+	atomic.AddInt64(&r.inFlight, 1)
+}
+
+// DecInFlight marks the end of a request being served. Callers should defer this right after
+// IncInFlight.
+func (r *Registry) DecInFlight() {
+	atomic.AddInt64(&r.inFlight, -1)
+}
+
+// ObserveRequest records one completed request's route, method, status, duration, and response
+// size into the registry.
+func (r *Registry) ObserveRequest(route, method string, status int, duration time.Duration, size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests[joinKey(route, method, strconv.Itoa(status))]++
+
+	routeMethodKey := joinKey(route, method)
+
+	if r.durations[routeMethodKey] == nil {
+		r.durations[routeMethodKey] = newHistogram(DurationBuckets)
+	}
+	r.durations[routeMethodKey].observe(duration.Seconds())
+
+	if r.sizes[routeMethodKey] == nil {
+		r.sizes[routeMethodKey] = newHistogram(SizeBuckets)
+	}
+	r.sizes[routeMethodKey].observe(float64(size))
+}
+
+// ServeExchange writes the current metrics snapshot as the HTTP response, in Prometheus text
+// exposition format. If token is non-empty, a matching "Authorization: Bearer <token>" header is
+// required.
+func (r *Registry) ServeExchange(ex *exchange.Exchange, token string) {
+	if token != "" && !util.ConstantTimeEqual(ex.Request.Header.Get("Authorization"), "Bearer "+token) {
+		ex.RespondWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	ex.ResponseWriter.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	ex.Write(r.render())
+}
+
+func (r *Registry) render() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP httpbun_requests_total Total number of HTTP requests handled.\n")
+	b.WriteString("# TYPE httpbun_requests_total counter\n")
+	for _, key := range sortedKeys(r.requests) {
+		route, method, status := splitKey3(key)
+		fmt.Fprintf(&b, "httpbun_requests_total{route=%q,method=%q,status=%q} %d\n", route, method, status, r.requests[key])
+	}
+
+	b.WriteString("# HELP httpbun_requests_in_flight Number of requests currently being served.\n")
+	b.WriteString("# TYPE httpbun_requests_in_flight gauge\n")
+	fmt.Fprintf(&b, "httpbun_requests_in_flight %d\n", atomic.LoadInt64(&r.inFlight))
+
+	writeHistograms(&b, "httpbun_request_duration_seconds", "Request duration in seconds.", r.durations)
+	writeHistograms(&b, "httpbun_response_size_bytes", "Response body size in bytes.", r.sizes)
+
+	return b.String()
+}
+
+func writeHistograms(b *strings.Builder, name, help string, histograms map[string]*histogram) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+
+	for _, key := range sortedKeys(histograms) {
+		route, method := splitKey2(key)
+		h := histograms[key]
+
+		for i, bound := range h.buckets {
+			fmt.Fprintf(b, "%s_bucket{route=%q,method=%q,le=%q} %d\n", name, route, method, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket{route=%q,method=%q,le=\"+Inf\"} %d\n", name, route, method, h.count)
+		fmt.Fprintf(b, "%s_sum{route=%q,method=%q} %s\n", name, route, method, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count{route=%q,method=%q} %d\n", name, route, method, h.count)
+	}
+}
+
+func joinKey(parts ...string) string {
+	return strings.Join(parts, labelSep)
+}
+
+func splitKey2(key string) (a, b string) {
+	parts := strings.Split(key, labelSep)
+	return parts[0], parts[1]
+}
+
+func splitKey3(key string) (a, b, c string) {
+	parts := strings.Split(key, labelSep)
+	return parts[0], parts[1], parts[2]
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
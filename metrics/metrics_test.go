@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryRender(t *testing.T) {
+	reg := NewRegistry()
+	reg.ObserveRequest("get", "GET", 200, 12*time.Millisecond, 42)
+
+	out := reg.render()
+
+	if !strings.Contains(out, `httpbun_requests_total{route="get",method="GET",status="200"} 1`) {
+		t.Errorf("missing request counter line in output:\n%s", out)
+	}
+	if !strings.Contains(out, `httpbun_request_duration_seconds_count{route="get",method="GET"} 1`) {
+		t.Errorf("missing duration count line in output:\n%s", out)
+	}
+	if !strings.Contains(out, `httpbun_response_size_bytes_sum{route="get",method="GET"} 42`) {
+		t.Errorf("missing size sum line in output:\n%s", out)
+	}
+}
@@ -0,0 +1,236 @@
+package routes
+
+import (
+	"encoding/xml"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sharat87/httpbun/exchange"
+	"github.com/sharat87/httpbun/util"
+)
+
+// Route pairs a compiled path pattern with the handler that should run when it matches. Named
+// capture groups in Pat (and HostPat, if set) end up in Exchange.Fields. Name is a short, stable
+// identifier for the route, used where the path itself would be too high-cardinality (e.g.
+// metrics labels).
+type Route struct {
+	Name string
+	// HostPat, if non-nil, is matched against the request's Host in addition to Pat. A nil
+	// HostPat means the route applies regardless of host, which is the behavior every route had
+	// before host-aware routing existed.
+	HostPat *regexp.Regexp
+	Pat     regexp.Regexp
+	Fn      exchange.HandlerFn
+}
+
+func newRoute(name, pat string, fn exchange.HandlerFn) Route {
+	return Route{
+		Name: name,
+		Pat:  *regexp.MustCompile("^" + pat + "$"),
+		Fn:   fn,
+	}
+}
+
+// newHostRoute is like newRoute, but additionally scopes the route to requests whose Host
+// matches hostPat. hostPat may be a plain hostname or a single leading wildcard label, e.g.
+// "*.mock.example.com", in which case the matched label is bound to Fields["subdomain"].
+func newHostRoute(name, hostPat, pat string, fn exchange.HandlerFn) Route {
+	route := newRoute(name, pat, fn)
+	route.HostPat = compileHostPattern(hostPat)
+	return route
+}
+
+func compileHostPattern(pat string) *regexp.Regexp {
+	if rest, ok := strings.CutPrefix(pat, "*."); ok {
+		return regexp.MustCompile(`^(?P<subdomain>[^.]+)\.` + regexp.QuoteMeta(rest) + `$`)
+	}
+	return regexp.MustCompile("^" + regexp.QuoteMeta(pat) + "$")
+}
+
+// GetRoutes returns the built-in fixture routes, in the order they should be matched.
+func GetRoutes() []Route {
+	return []Route{
+		newRoute("ip", "/ip", handleIP),
+		newRoute("headers", "/headers", handleHeaders),
+		newRoute("get", "/get", handleGet),
+		newHostRoute("host-info", "*.mock.example.com", "/host-info", handleHostInfo),
+		newRoute("anything", "/anything", handleAnything),
+		newRoute("stream-sse", `/stream-sse/(?P<count>\d+)`, handleStreamSSE),
+		newRoute("stream-ndjson", `/stream-ndjson/(?P<count>\d+)`, handleStreamNDJSON),
+		newRoute("stream-delayed", `/stream-delayed/(?P<count>\d+)/(?P<interval_ms>\d+)`, handleStreamDelayed),
+	}
+}
+
+func handleIP(ex *exchange.Exchange) {
+	ex.WriteJSON(map[string]any{
+		"ip": ex.FindIncomingIPAddress(),
+	})
+}
+
+func handleHeaders(ex *exchange.Exchange) {
+	ex.WriteJSON(map[string]any{
+		"headers": ex.ExposableHeadersMap(),
+	})
+}
+
+func handleGet(ex *exchange.Exchange) {
+	ex.WriteJSON(map[string]any{
+		"args":    ex.Request.URL.Query(),
+		"headers": ex.ExposableHeadersMap(),
+		"origin":  ex.FindIncomingIPAddress(),
+		"url":     ex.FullUrl(),
+	})
+}
+
+// handleHostInfo demonstrates host-scoped fixtures: it's only reachable via a subdomain of
+// mock.example.com, and echoes back the subdomain label a wildcard DNS record would expose, so a
+// single httpbun instance can stand in for per-tenant mocks.
+func handleHostInfo(ex *exchange.Exchange) {
+	ex.WriteJSON(map[string]any{
+		"subdomain": ex.Field("subdomain"),
+		"host":      ex.Request.Host,
+	})
+}
+
+// anythingPayload is the body shape /anything accepts, whether it arrives as JSON, XML, or a
+// form post.
+type anythingPayload struct {
+	Name  string `json:"name" xml:"name" form:"name"`
+	Value string `json:"value" xml:"value" form:"value"`
+}
+
+// anythingResponse is what /anything echoes back, rendered as JSON, XML, or plain text depending
+// on the client's Accept header.
+type anythingResponse struct {
+	XMLName xml.Name        `json:"-" xml:"anything"`
+	Method  string          `json:"method" xml:"method"`
+	URL     string          `json:"url" xml:"url"`
+	Payload anythingPayload `json:"payload" xml:"payload"`
+}
+
+// handleAnything accepts JSON, XML, or form-encoded bodies and echoes them back in whichever of
+// JSON, XML, or plain text the client's Accept header prefers, using Exchange.BindBody and
+// Exchange.WriteNegotiated instead of parsing each content type by hand.
+func handleAnything(ex *exchange.Exchange) {
+	var payload anythingPayload
+
+	if ex.Request.ContentLength != 0 {
+		if err := ex.BindBody(&payload); err != nil {
+			// BindBody has already written the error response.
+			return
+		}
+	}
+
+	ex.WriteNegotiated(anythingResponse{
+		Method:  ex.Request.Method,
+		URL:     ex.FullUrl(),
+		Payload: payload,
+	})
+}
+
+// handleStreamSSE implements /stream-sse/:count, emitting count Server-Sent Events with a
+// heartbeat comment every 15s, using Exchange.SSE.
+func handleStreamSSE(ex *exchange.Exchange) {
+	count, err := strconv.Atoi(ex.Field("count"))
+	if err != nil {
+		ex.RespondError(http.StatusBadRequest, "invalid_count", err.Error())
+		return
+	}
+
+	ch := make(chan exchange.SSEEvent)
+	go generateSSEEvents(ex, count, ch)
+	ex.SSE(ch, 15*time.Second)
+}
+
+// handleStreamNDJSON implements /stream-ndjson/:count, writing count newline-delimited JSON
+// records as fast as the client can read them, using Exchange.StreamJSON.
+func handleStreamNDJSON(ex *exchange.Exchange) {
+	count, err := strconv.Atoi(ex.Field("count"))
+	if err != nil {
+		ex.RespondError(http.StatusBadRequest, "invalid_count", err.Error())
+		return
+	}
+
+	ch := make(chan any)
+	go generateStreamRecords(ex, count, 0, ch)
+	ex.StreamJSON(ch)
+}
+
+// handleStreamDelayed implements /stream-delayed/:count/:interval_ms, writing count
+// newline-delimited JSON records spaced intervalMs apart, so clients can exercise slow-stream
+// and partial-read handling.
+func handleStreamDelayed(ex *exchange.Exchange) {
+	count, err := strconv.Atoi(ex.Field("count"))
+	if err != nil {
+		ex.RespondError(http.StatusBadRequest, "invalid_count", err.Error())
+		return
+	}
+
+	intervalMs, err := strconv.Atoi(ex.Field("interval_ms"))
+	if err != nil {
+		ex.RespondError(http.StatusBadRequest, "invalid_interval_ms", err.Error())
+		return
+	}
+
+	ch := make(chan any)
+	go generateStreamRecords(ex, count, time.Duration(intervalMs)*time.Millisecond, ch)
+	ex.StreamJSON(ch)
+}
+
+// generateStreamRecords sends count synthetic {"seq": ...} records to ch, spaced interval apart
+// (if non-zero), closing ch once done. It stops early via ex.StreamDeadline, so a slow or
+// disconnected client (or a server-side HTTPBUN_MAX_STREAM_SECONDS cap) can't leave this goroutine
+// blocked forever on a send nobody's reading.
+func generateStreamRecords(ex *exchange.Exchange, count int, interval time.Duration, ch chan<- any) {
+	defer close(ch)
+
+	ctx, cancel := ex.StreamDeadline()
+	defer cancel()
+
+	var ticker *time.Ticker
+	if interval > 0 {
+		ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+	}
+
+	for seq := 0; seq < count; seq++ {
+		if ticker != nil && seq > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- map[string]any{"seq": seq}:
+		}
+	}
+}
+
+// generateSSEEvents sends count synthetic events to ch, closing it once done. See
+// generateStreamRecords for why it also selects on ex.StreamDeadline.
+func generateSSEEvents(ex *exchange.Exchange, count int, ch chan<- exchange.SSEEvent) {
+	defer close(ch)
+
+	ctx, cancel := ex.StreamDeadline()
+	defer cancel()
+
+	for seq := 0; seq < count; seq++ {
+		event := exchange.SSEEvent{
+			ID:   strconv.Itoa(seq),
+			Data: string(util.ToJsonMust(map[string]any{"seq": seq})),
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- event:
+		}
+	}
+}
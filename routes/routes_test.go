@@ -0,0 +1,34 @@
+package routes
+
+import "testing"
+
+func TestCompileHostPatternWildcard(t *testing.T) {
+	pat := compileHostPattern("*.mock.example.com")
+
+	match := pat.FindStringSubmatch("tenant-a.mock.example.com")
+	if match == nil {
+		t.Fatal("expected a match for a wildcard subdomain")
+	}
+
+	names := pat.SubexpNames()
+	for i, name := range names {
+		if name == "subdomain" && match[i] != "tenant-a" {
+			t.Errorf("subdomain = %q, want %q", match[i], "tenant-a")
+		}
+	}
+
+	if pat.MatchString("mock.example.com") {
+		t.Error("expected no match for the bare host, without a subdomain label")
+	}
+}
+
+func TestCompileHostPatternExact(t *testing.T) {
+	pat := compileHostPattern("example.com")
+
+	if !pat.MatchString("example.com") {
+		t.Error("expected exact host to match")
+	}
+	if pat.MatchString("sub.example.com") {
+		t.Error("expected exact host pattern not to match a subdomain")
+	}
+}
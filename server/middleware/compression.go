@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Compression gzip- or deflate-encodes responses whose Content-Type is in contentTypes (any
+// type, if contentTypes is empty) and whose body is at least minBytes long, when the client's
+// Accept-Encoding allows it. It wraps ResponseWriter transparently, so every existing Write*
+// helper on Exchange benefits without change.
+func Compression(minBytes int, contentTypes []string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			encoding := pickEncoding(req.Header.Get("Accept-Encoding"))
+			if encoding == "" {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			cw := &compressingWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minBytes:       minBytes,
+				contentTypes:   contentTypes,
+			}
+			defer cw.Close()
+
+			next.ServeHTTP(cw, req)
+		})
+	}
+}
+
+func pickEncoding(acceptEncoding string) string {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		switch strings.TrimSpace(strings.SplitN(part, ";", 2)[0]) {
+		case "gzip":
+			return "gzip"
+		case "deflate":
+			return "deflate"
+		}
+	}
+	return ""
+}
+
+// compressingWriter buffers up to minBytes of the response body before deciding whether it
+// qualifies for compression (by Content-Type and actual body size, since handlers here never
+// set Content-Length up front), then transparently streams the body through the chosen encoder
+// if so. The decision is forced early, on whatever's buffered so far, by an explicit Flush (so
+// streaming responses aren't held back) or by Close (so short responses still get written).
+type compressingWriter struct {
+	http.ResponseWriter
+	encoding     string
+	minBytes     int
+	contentTypes []string
+	statusCode   int
+	buf          []byte
+	decided      bool
+	compress     bool
+	enc          io.WriteCloser
+}
+
+func (w *compressingWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+func (w *compressingWriter) Write(p []byte) (int, error) {
+	if w.decided {
+		if w.compress {
+			return w.enc.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.minBytes {
+		w.decide()
+	}
+
+	return len(p), nil
+}
+
+// decide settles whether the response should be compressed, based on whatever's been buffered so
+// far, then writes the (possibly rewritten) headers and flushes the buffer through.
+func (w *compressingWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.compress = typeAllowed(w.Header().Get("Content-Type"), w.contentTypes) && len(w.buf) >= w.minBytes
+
+	if w.compress {
+		w.Header().Del("Content-Length")
+		w.Header().Set("Content-Encoding", w.encoding)
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+
+	statusCode := w.statusCode
+	if statusCode == 0 {
+		// No explicit WriteHeader call means an implicit 200, same as net/http's default.
+		statusCode = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+
+	buffered := w.buf
+	w.buf = nil
+
+	if w.compress {
+		w.enc = w.newEncoder()
+		if len(buffered) > 0 {
+			_, _ = w.enc.Write(buffered)
+		}
+	} else if len(buffered) > 0 {
+		_, _ = w.ResponseWriter.Write(buffered)
+	}
+}
+
+func typeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, t := range allowed {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *compressingWriter) newEncoder() io.WriteCloser {
+	if w.encoding == "gzip" {
+		return gzip.NewWriter(w.ResponseWriter)
+	}
+	fw, _ := flate.NewWriter(w.ResponseWriter, flate.DefaultCompression)
+	return fw
+}
+
+// Close decides (if a response that never reached minBytes or called Flush hasn't already) and
+// flushes and closes the underlying encoder, if compression kicked in. Safe to call unconditionally.
+func (w *compressingWriter) Close() error {
+	if !w.decided {
+		w.decide()
+	}
+	if w.enc != nil {
+		return w.enc.Close()
+	}
+	return nil
+}
+
+// Flush implements http.Flusher, so downstream streaming helpers keep working through
+// compression. It forces an early decide if one hasn't happened yet, so a streaming handler's
+// first flushed chunk isn't held back waiting for minBytes to accumulate.
+func (w *compressingWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if f, ok := w.enc.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressionSkipsResponsesUnderMinBytes(t *testing.T) {
+	handler := Compression(1024, nil)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"a":1}`))
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if enc := rec.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want no compression under minBytes", enc)
+	}
+	if rec.Body.String() != `{"a":1}` {
+		t.Errorf("body = %q, want the response written verbatim", rec.Body.String())
+	}
+}
+
+func TestCompressionGzipsResponsesAtOrOverMinBytes(t *testing.T) {
+	body := strings.Repeat("x", 2000)
+
+	handler := Compression(1024, nil)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(body))
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if enc := rec.Header().Get("Content-Encoding"); enc != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want %q", enc, "gzip")
+	}
+
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	got, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("reading gzip body: %v", err)
+	}
+	if string(got) != body {
+		t.Errorf("decompressed body = %q, want original body", got)
+	}
+}
+
+func TestCompressionSkipsDisallowedContentType(t *testing.T) {
+	body := strings.Repeat("x", 2000)
+
+	handler := Compression(1024, []string{"application/json"})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(body))
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if enc := rec.Header().Get("Content-Encoding"); enc != "" {
+		t.Errorf("Content-Encoding = %q, want no compression for a disallowed content type", enc)
+	}
+	if rec.Body.String() != body {
+		t.Error("expected the body to be written verbatim when compression is skipped")
+	}
+}
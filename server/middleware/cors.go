@@ -0,0 +1,28 @@
+package middleware
+
+import "net/http"
+
+// CORS mirrors the incoming Origin and Access-Control-Request-* headers back onto the response.
+// The exact origin (not "*") is echoed back, since "*" is rejected by browsers when the request
+// includes credentials; see
+// <https://developer.mozilla.org/en-US/docs/Web/HTTP/CORS/Errors/CORSNotSupportingCredentials>.
+func CORS() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if origin := headerLast(req.Header, "Origin"); origin != "" {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if headers := headerLast(req.Header, "Access-Control-Request-Headers"); headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+
+			if method := headerLast(req.Header, "Access-Control-Request-Method"); method != "" {
+				w.Header().Set("Access-Control-Allow-Methods", method)
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// Package middleware provides composable http.Handler wrappers that run ahead of route
+// dispatch, for cross-cutting concerns (compression, proxy-header trust, CORS, ...) that used
+// to be hard-coded inline in exchange.New.
+package middleware
+
+import "net/http"
+
+// Middleware wraps an http.Handler to add behavior before and/or after it runs.
+type Middleware func(next http.Handler) http.Handler
+
+// Chain composes middlewares around final, so the first middleware in the list is outermost
+// (runs first on the way in, last on the way out).
+func Chain(final http.Handler, middlewares ...Middleware) http.Handler {
+	handler := final
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type ctxKey int
+
+const schemeCtxKey ctxKey = iota
+
+// WithScheme attaches the scheme resolved for req (typically from a forwarding header) to its
+// context, for exchange.Exchange.FindScheme to pick up downstream.
+func WithScheme(req *http.Request, scheme string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), schemeCtxKey, scheme))
+}
+
+// SchemeFromContext retrieves a scheme previously attached with WithScheme.
+func SchemeFromContext(ctx context.Context) (string, bool) {
+	scheme, ok := ctx.Value(schemeCtxKey).(string)
+	return scheme, ok
+}
+
+// ProxyHeaders rewrites Request.RemoteAddr, Request.Host, and the request's resolved scheme from
+// standard forwarding headers (X-Forwarded-For/Proto/Host, falling back to the RFC 7239
+// Forwarded header's for/proto/host params, respectively, when the legacy header is absent), but
+// only when the immediate peer (req.RemoteAddr) falls within trustedCIDRs. Requests from anywhere
+// else keep these headers as opaque, client-supplied data, since honoring them unconditionally
+// would let any client spoof its own IP and scheme.
+func ProxyHeaders(trustedCIDRs []string) Middleware {
+	nets := parseCIDRs(trustedCIDRs)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if isTrustedPeer(req.RemoteAddr, nets) {
+				forwarded := headerLast(req.Header, "Forwarded")
+
+				forwardedFor := headerLast(req.Header, "X-Forwarded-For")
+				if forwardedFor == "" {
+					forwardedFor = forwardedParam(forwarded, "for")
+				}
+				if forwardedFor != "" {
+					req.RemoteAddr = net.JoinHostPort(forwardedForHost(firstCSV(forwardedFor)), "0")
+				}
+
+				forwardedHost := headerLast(req.Header, "X-Forwarded-Host")
+				if forwardedHost == "" {
+					forwardedHost = forwardedParam(forwarded, "host")
+				}
+				if forwardedHost != "" {
+					req.Host = forwardedHost
+				}
+
+				scheme := headerLast(req.Header, "X-Forwarded-Proto")
+				if scheme == "" {
+					scheme = forwardedParam(forwarded, "proto")
+				}
+				if scheme != "" {
+					req = WithScheme(req, scheme)
+				}
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
+
+func parseCIDRs(cidrs []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range cidrs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			nets = append(nets, ipNet)
+		}
+	}
+	return nets
+}
+
+func isTrustedPeer(remoteAddr string, nets []*net.IPNet) bool {
+	if len(nets) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func firstCSV(value string) string {
+	return strings.TrimSpace(strings.SplitN(value, ",", 2)[0])
+}
+
+// forwardedForHost strips an optional port (and, for IPv6, the surrounding brackets) off a
+// single X-Forwarded-For/Forwarded "for" value, leaving just the address, so it can be rejoined
+// with net.JoinHostPort without doubling up on brackets or a stray port.
+func forwardedForHost(value string) string {
+	value = strings.Trim(value, `"`)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return host
+	}
+	return strings.Trim(value, "[]")
+}
+
+func headerLast(header http.Header, name string) string {
+	values := header[http.CanonicalHeaderKey(name)]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// forwardedParam pulls a single param (e.g. "proto", "for") out of an RFC 7239 Forwarded header.
+// Only the first hop's value is used; relaying through multiple proxies isn't supported here.
+func forwardedParam(forwarded, param string) string {
+	forwarded = strings.SplitN(forwarded, ",", 2)[0]
+	for _, pair := range strings.Split(forwarded, ";") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), param) {
+			return strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsTrustedPeer(t *testing.T) {
+	nets := parseCIDRs([]string{"10.0.0.0/8"})
+
+	if !isTrustedPeer("10.1.2.3:1234", nets) {
+		t.Error("expected an address inside the trusted CIDR to be trusted")
+	}
+	if isTrustedPeer("203.0.113.5:1234", nets) {
+		t.Error("expected an address outside the trusted CIDR to not be trusted")
+	}
+	if isTrustedPeer("10.1.2.3:1234", parseCIDRs(nil)) {
+		t.Error("expected no peer to be trusted when no CIDRs are configured")
+	}
+}
+
+func TestForwardedParam(t *testing.T) {
+	forwarded := `for=192.0.2.60;proto=https;host=example.test`
+
+	if got := forwardedParam(forwarded, "for"); got != "192.0.2.60" {
+		t.Errorf(`forwardedParam(forwarded, "for") = %q, want %q`, got, "192.0.2.60")
+	}
+	if got := forwardedParam(forwarded, "proto"); got != "https" {
+		t.Errorf(`forwardedParam(forwarded, "proto") = %q, want %q`, got, "https")
+	}
+	if got := forwardedParam(forwarded, "host"); got != "example.test" {
+		t.Errorf(`forwardedParam(forwarded, "host") = %q, want %q`, got, "example.test")
+	}
+}
+
+func TestProxyHeadersRewritesFromForwardedHeader(t *testing.T) {
+	handler := ProxyHeaders([]string{"10.0.0.0/8"})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.RemoteAddr != "203.0.113.7:0" {
+			t.Errorf("RemoteAddr = %q, want %q", req.RemoteAddr, "203.0.113.7:0")
+		}
+		if req.Host != "example.test" {
+			t.Errorf("Host = %q, want %q", req.Host, "example.test")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.1.2.3:4567"
+	req.Header.Set("Forwarded", `for=203.0.113.7;host=example.test;proto=https`)
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestProxyHeadersBracketsIPv6RemoteAddr(t *testing.T) {
+	handler := ProxyHeaders([]string{"10.0.0.0/8"})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.RemoteAddr != "[2001:db8::1]:0" {
+			t.Errorf("RemoteAddr = %q, want %q", req.RemoteAddr, "[2001:db8::1]:0")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.1.2.3:4567"
+	req.Header.Set("X-Forwarded-For", "2001:db8::1")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestProxyHeadersIgnoresUntrustedPeer(t *testing.T) {
+	handler := ProxyHeaders([]string{"10.0.0.0/8"})(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.RemoteAddr != "203.0.113.5:4567" {
+			t.Errorf("RemoteAddr = %q, want unchanged %q", req.RemoteAddr, "203.0.113.5:4567")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "203.0.113.5:4567"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
@@ -2,13 +2,17 @@ package server
 
 import (
 	"context"
+	"github.com/sharat87/httpbun/admin"
 	"github.com/sharat87/httpbun/exchange"
+	"github.com/sharat87/httpbun/metrics"
 	"github.com/sharat87/httpbun/routes"
+	"github.com/sharat87/httpbun/server/middleware"
 	"github.com/sharat87/httpbun/server/spec"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -17,6 +21,8 @@ type Server struct {
 	*http.Server
 	spec    spec.Spec
 	routes  []routes.Route
+	metrics *metrics.Registry
+	admin   *admin.Registry
 	closeCh chan error
 }
 
@@ -32,7 +38,43 @@ func StartNew(spec spec.Spec) Server {
 		routes:  routes.GetRoutes(),
 		closeCh: make(chan error, 1),
 	}
-	server.Handler = server
+
+	if spec.MetricsEnabled {
+		reg := metrics.NewRegistry()
+		server.metrics = reg
+		server.routes = append([]routes.Route{{
+			Name: "metrics",
+			Pat:  *regexp.MustCompile(`^/metrics$`),
+			Fn: func(ex *exchange.Exchange) {
+				reg.ServeExchange(ex, spec.MetricsToken)
+			},
+		}}, server.routes...)
+	}
+
+	if spec.AdminToken != "" {
+		reg := admin.NewRegistry(spec.AdminRoutesFile)
+		server.admin = reg
+		server.routes = append([]routes.Route{
+			{
+				Name: "admin-routes",
+				Pat:  *regexp.MustCompile(`^/_admin/routes(?:/(?P<id>[^/]+))?$`),
+				Fn:   adminHandler(spec.AdminToken, reg.HandleRoutes),
+			},
+			{
+				Name: "admin-redirects",
+				Pat:  *regexp.MustCompile(`^/_admin/redirects(?:/(?P<id>[^/]+))?$`),
+				Fn:   adminHandler(spec.AdminToken, reg.HandleRedirects),
+			},
+		}, server.routes...)
+	}
+
+	var middlewares []middleware.Middleware
+	if spec.CompressionEnabled {
+		middlewares = append(middlewares, middleware.Compression(spec.CompressionMinBytes, spec.CompressionTypes))
+	}
+	middlewares = append(middlewares, middleware.ProxyHeaders(spec.TrustedProxyCIDRs), middleware.CORS())
+
+	server.Handler = middleware.Chain(http.HandlerFunc(server.route), middlewares...)
 
 	listener, err := net.Listen("tcp", spec.BindTarget)
 	if err != nil {
@@ -69,7 +111,21 @@ func (s Server) CloseAndWait() {
 	log.Print(s.Wait())
 }
 
-func (s Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+// adminHandler wraps an admin.Registry handler with the bearer-token check every admin endpoint
+// requires.
+func adminHandler(token string, handler exchange.HandlerFn) exchange.HandlerFn {
+	return func(ex *exchange.Exchange) {
+		if !admin.Authenticate(ex, token) {
+			ex.RespondWithStatus(http.StatusUnauthorized)
+			return
+		}
+		handler(ex)
+	}
+}
+
+// route dispatches a request first to any matching operator-defined route or redirect, then to
+// the first matching built-in route, after all configured middlewares have had a chance to run.
+func (s Server) route(w http.ResponseWriter, req *http.Request) {
 	if !strings.HasPrefix(req.URL.Path, s.spec.PathPrefix) {
 		http.NotFound(w, req)
 		return
@@ -77,6 +133,12 @@ func (s Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 	ex := exchange.New(w, req, s.spec)
 
+	if s.metrics != nil {
+		s.metrics.IncInFlight()
+		defer s.metrics.DecInFlight()
+	}
+	start := time.Now()
+
 	incomingIP := ex.FindIncomingIPAddress()
 	log.Printf(
 		"From ip=%s %s %s%s",
@@ -86,13 +148,41 @@ func (s Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		req.URL.String(),
 	)
 
+	// The /_admin/... management paths are never shadowed by operator-registered routes or
+	// redirects, even a catch-all pattern, so the admin API always stays reachable (including to
+	// remove the offending custom route).
+	if s.admin != nil && !strings.HasPrefix(ex.URL.Path, "/_admin/") {
+		if customRoute := s.admin.MatchRoute(ex.URL.Path, req.Method); customRoute != nil {
+			customRoute.Serve(ex)
+			s.recordMetrics("admin-custom-route", req, ex, start)
+			return
+		}
+		if redirect := s.admin.MatchRedirect(ex.URL.Path); redirect != nil {
+			redirect.Serve(ex)
+			s.recordMetrics("admin-custom-redirect", req, ex, start)
+			return
+		}
+	}
+
 	for _, route := range s.routes {
-		if ex.MatchAndLoadFields(route.Pat) {
+		if ex.MatchAndLoadFields(route.HostPat, route.Pat) {
 			route.Fn(ex)
+			s.recordMetrics(route.Name, req, ex, start)
 			return
 		}
 	}
 
 	log.Printf("NotFound ip=%s %s %s", incomingIP, req.Method, req.URL.String())
-	http.NotFound(w, req)
+	http.NotFound(ex.ResponseWriter, req)
+	s.recordMetrics("notfound", req, ex, start)
+}
+
+// recordMetrics reports one finished request to the metrics registry, if instrumentation is
+// enabled. It reads the status and size off ex's statusRecorder, so it must run after the route
+// handler (or http.NotFound) has written the response.
+func (s Server) recordMetrics(routeName string, req *http.Request, ex *exchange.Exchange, start time.Time) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.ObserveRequest(routeName, req.Method, ex.ResponseStatus(), time.Since(start), ex.ResponseSize())
 }
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sharat87/httpbun/admin"
+	"github.com/sharat87/httpbun/exchange"
+	"github.com/sharat87/httpbun/metrics"
+	"github.com/sharat87/httpbun/routes"
+	"github.com/sharat87/httpbun/server/spec"
+)
+
+// builtinRoute builds a Route whose handler writes body verbatim, for asserting which route won.
+func builtinRoute(name, pat, body string) routes.Route {
+	return routes.Route{
+		Name: name,
+		Pat:  *regexp.MustCompile(pat),
+		Fn: func(ex *exchange.Exchange) {
+			ex.Write(body)
+		},
+	}
+}
+
+func TestRouteAdminRoutePreemptsBuiltinAtSamePath(t *testing.T) {
+	reg := admin.NewRegistry("")
+	registerAdminRoute(t, reg, `^/foo$`, "admin")
+
+	s := Server{
+		spec:   spec.Spec{},
+		routes: []routes.Route{builtinRoute("foo", "^/foo$", "built-in")},
+		admin:  reg,
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/foo", nil)
+	s.route(w, req)
+
+	if got := w.Body.String(); got != "admin" {
+		t.Errorf("body = %q, want %q (admin route should take priority over the built-in one)", got, "admin")
+	}
+}
+
+func TestRouteAdminRouteMatchesUnderPathPrefix(t *testing.T) {
+	reg := admin.NewRegistry("")
+	registerAdminRoute(t, reg, `^/foo$`, "admin")
+
+	s := Server{
+		spec:  spec.Spec{PathPrefix: "/api"},
+		admin: reg,
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/foo", nil)
+	s.route(w, req)
+
+	if got := w.Body.String(); got != "admin" {
+		t.Errorf("body = %q, want %q (admin route should match against the PathPrefix-trimmed path)", got, "admin")
+	}
+}
+
+func TestRouteRecordsMetricsForBuiltinAdminAndNotFound(t *testing.T) {
+	reg := admin.NewRegistry("")
+	registerAdminRoute(t, reg, `^/custom$`, "admin")
+
+	met := metrics.NewRegistry()
+
+	s := Server{
+		spec:    spec.Spec{},
+		routes:  []routes.Route{builtinRoute("foo", "^/foo$", "built-in")},
+		admin:   reg,
+		metrics: met,
+	}
+
+	for _, path := range []string{"/foo", "/custom", "/does-not-exist"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		s.route(w, req)
+	}
+
+	out := renderMetrics(t, met)
+
+	for _, route := range []string{"foo", "admin-custom-route", "notfound"} {
+		want := `httpbun_requests_total{route="` + route + `",method="GET"`
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing counter for route %q:\n%s", route, out)
+		}
+	}
+}
+
+func TestRouteNeverShadowsAdminManagementPathsWithCustomRoutes(t *testing.T) {
+	reg := admin.NewRegistry("")
+	registerAdminRoute(t, reg, `^/.*$`, "caught")
+
+	const adminToken = "secret"
+	s := Server{
+		spec: spec.Spec{},
+		routes: []routes.Route{
+			{
+				Name: "admin-routes",
+				Pat:  *regexp.MustCompile(`^/_admin/routes(?:/(?P<id>[^/]+))?$`),
+				Fn:   adminHandler(adminToken, reg.HandleRoutes),
+			},
+		},
+		admin: reg,
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("DELETE", "/_admin/routes/1", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	s.route(w, req)
+
+	if got := w.Body.String(); got == "caught" {
+		t.Fatal("a catch-all custom route shadowed the real /_admin/routes management endpoint")
+	}
+}
+
+// registerAdminRoute installs a route matching pattern and answering with body into reg, by
+// calling the admin API's POST handler directly rather than going through Server.route, since
+// these tests exercise Server.route, not the admin handlers (those are covered in
+// admin/handlers_test.go).
+func registerAdminRoute(t *testing.T, reg *admin.Registry, pattern, body string) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/_admin/routes", strings.NewReader(
+		`{"pattern": "`+pattern+`", "status": 200, "body": "`+body+`"}`,
+	))
+	ex := exchange.New(w, req, spec.Spec{})
+	reg.HandleRoutes(ex)
+
+	if w.Code != 201 {
+		t.Fatalf("registering admin route failed with status %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// renderMetrics drives met's /metrics handler and returns the Prometheus text it produces.
+func renderMetrics(t *testing.T, met *metrics.Registry) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	ex := exchange.New(w, req, spec.Spec{})
+	met.ServeExchange(ex, "")
+
+	return w.Body.String()
+}
@@ -0,0 +1,90 @@
+package spec
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Spec carries the configuration a Server is started with. It's built once, from env vars (or
+// flags, in tests), and passed down to the exchange and route layers rather than read from
+// globals, so the whole request path stays testable.
+type Spec struct {
+	BindTarget string
+	PathPrefix string
+	Commit     string
+
+	// TrustedProxyCIDRs lists the CIDR ranges allowed to set forwarding headers
+	// (X-Forwarded-For/Proto/Host, Forwarded). Empty means no peer is trusted.
+	TrustedProxyCIDRs []string
+
+	// CompressionEnabled turns on the gzip/deflate response-compression middleware.
+	CompressionEnabled bool
+	// CompressionMinBytes is the smallest response body (by actual body size, since handlers
+	// don't set Content-Length up front) worth compressing.
+	CompressionMinBytes int
+	// CompressionTypes is a Content-Type prefix allowlist for compression; empty means any type.
+	CompressionTypes []string
+
+	// MetricsEnabled turns on the /metrics endpoint and request instrumentation.
+	MetricsEnabled bool
+	// MetricsToken, if non-empty, is the bearer token required to read /metrics.
+	MetricsToken string
+
+	// AdminToken is the bearer token required to use the /_admin/routes and /_admin/redirects
+	// API. The admin surface is only registered when this is non-empty.
+	AdminToken string
+	// AdminRoutesFile, if set, is where the admin registry persists routes and redirects across
+	// restarts.
+	AdminRoutesFile string
+}
+
+// FromEnv builds a Spec from the HTTPBUN_* environment variables the proxy-header, compression,
+// metrics, and admin API read. BindTarget, PathPrefix, and Commit aren't populated here; callers
+// that need them set those fields directly (flags in tests, build info at link time, etc.).
+func FromEnv() Spec {
+	return Spec{
+		TrustedProxyCIDRs: splitCSVEnv("HTTPBUN_TRUSTED_PROXIES"),
+
+		CompressionEnabled:  boolEnv("HTTPBUN_COMPRESSION"),
+		CompressionMinBytes: intEnv("HTTPBUN_COMPRESSION_MIN_BYTES", 0),
+		CompressionTypes:    splitCSVEnv("HTTPBUN_COMPRESSION_TYPES"),
+
+		MetricsEnabled: boolEnv("HTTPBUN_METRICS"),
+		MetricsToken:   os.Getenv("HTTPBUN_METRICS_TOKEN"),
+
+		AdminToken:      os.Getenv("HTTPBUN_ADMIN_TOKEN"),
+		AdminRoutesFile: os.Getenv("HTTPBUN_ROUTES_FILE"),
+	}
+}
+
+func boolEnv(name string) bool {
+	value, err := strconv.ParseBool(os.Getenv(name))
+	return err == nil && value
+}
+
+func intEnv(name string, fallback int) int {
+	if raw := os.Getenv(name); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+// splitCSVEnv reads name as a comma-separated list, trimming whitespace around each entry and
+// dropping empty ones. An unset or empty env var returns nil, same as a zero-value Spec field.
+func splitCSVEnv(name string) []string {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil
+	}
+
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
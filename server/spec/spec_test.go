@@ -0,0 +1,65 @@
+package spec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromEnvReadsCompressionAndProxyVars(t *testing.T) {
+	t.Setenv("HTTPBUN_TRUSTED_PROXIES", "10.0.0.0/8, 192.168.0.0/16")
+	t.Setenv("HTTPBUN_COMPRESSION", "true")
+	t.Setenv("HTTPBUN_COMPRESSION_MIN_BYTES", "256")
+	t.Setenv("HTTPBUN_COMPRESSION_TYPES", "application/json,text/plain")
+
+	s := FromEnv()
+
+	if want := []string{"10.0.0.0/8", "192.168.0.0/16"}; !reflect.DeepEqual(s.TrustedProxyCIDRs, want) {
+		t.Errorf("TrustedProxyCIDRs = %v, want %v", s.TrustedProxyCIDRs, want)
+	}
+	if !s.CompressionEnabled {
+		t.Error("expected CompressionEnabled to be true")
+	}
+	if s.CompressionMinBytes != 256 {
+		t.Errorf("CompressionMinBytes = %d, want 256", s.CompressionMinBytes)
+	}
+	if want := []string{"application/json", "text/plain"}; !reflect.DeepEqual(s.CompressionTypes, want) {
+		t.Errorf("CompressionTypes = %v, want %v", s.CompressionTypes, want)
+	}
+}
+
+func TestFromEnvReadsMetricsVars(t *testing.T) {
+	t.Setenv("HTTPBUN_METRICS", "true")
+	t.Setenv("HTTPBUN_METRICS_TOKEN", "s3cret")
+
+	s := FromEnv()
+
+	if !s.MetricsEnabled {
+		t.Error("expected MetricsEnabled to be true")
+	}
+	if s.MetricsToken != "s3cret" {
+		t.Errorf("MetricsToken = %q, want %q", s.MetricsToken, "s3cret")
+	}
+}
+
+func TestFromEnvReadsAdminVars(t *testing.T) {
+	t.Setenv("HTTPBUN_ADMIN_TOKEN", "admin-s3cret")
+	t.Setenv("HTTPBUN_ROUTES_FILE", "/tmp/httpbun-routes.json")
+
+	s := FromEnv()
+
+	if s.AdminToken != "admin-s3cret" {
+		t.Errorf("AdminToken = %q, want %q", s.AdminToken, "admin-s3cret")
+	}
+	if s.AdminRoutesFile != "/tmp/httpbun-routes.json" {
+		t.Errorf("AdminRoutesFile = %q, want %q", s.AdminRoutesFile, "/tmp/httpbun-routes.json")
+	}
+}
+
+func TestFromEnvDefaultsToZeroValue(t *testing.T) {
+	s := FromEnv()
+
+	if s.CompressionEnabled || s.TrustedProxyCIDRs != nil || s.CompressionTypes != nil ||
+		s.MetricsEnabled || s.MetricsToken != "" || s.AdminToken != "" || s.AdminRoutesFile != "" {
+		t.Errorf("expected a zero-value Spec with no env vars set, got %+v", s)
+	}
+}
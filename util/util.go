@@ -0,0 +1,23 @@
+package util
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+)
+
+// ToJsonMust marshals data to JSON, panicking on failure. It's meant for values we construct
+// ourselves (maps, structs), where a marshal error means a bug in this code, not bad input.
+func ToJsonMust(data any) []byte {
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+// ConstantTimeEqual reports whether a and b are equal, comparing in time independent of where
+// they first differ. Use it instead of == whenever one side is a secret (a bearer token, for
+// instance), so a timing side-channel can't help an attacker guess it byte by byte.
+func ConstantTimeEqual(a, b string) bool {
+	return len(a) == len(b) && subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
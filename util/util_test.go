@@ -0,0 +1,15 @@
+package util
+
+import "testing"
+
+func TestConstantTimeEqual(t *testing.T) {
+	if !ConstantTimeEqual("Bearer s3cret", "Bearer s3cret") {
+		t.Error("expected equal strings to compare equal")
+	}
+	if ConstantTimeEqual("Bearer s3cret", "Bearer wrong") {
+		t.Error("expected different strings of the same length to compare unequal")
+	}
+	if ConstantTimeEqual("Bearer s3cret", "Bearer s3cre") {
+		t.Error("expected strings of different lengths to compare unequal")
+	}
+}